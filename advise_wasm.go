@@ -0,0 +1,39 @@
+package mmap
+
+// This file is named advise_wasm.go, not a plain advise.go, because
+// Advise/IsResident/LockRange/UnlockRange here call this backend's
+// m.advise/m.isResident/m.lockRange/m.unlockRange. Other platforms wrap
+// real madvise(2)/mincore(2) and need their own copy of this file (and
+// the Advise* constants) alongside their own lowercase primitives.
+
+// Advice values accepted by Advise, mirroring the madvise(2) flags.
+const (
+	AdviseRandom = iota
+	AdviseSequential
+	AdviseWillNeed
+	AdviseDontNeed
+)
+
+// Advise hints at how the mapping will be accessed (see the Advise*
+// constants above), e.g. for scan-heavy workloads. This (WASM) backend
+// treats it as a no-op, since there's no kernel page cache to hint.
+func (m MMap) Advise(advice int) error {
+	return m.advise(advice)
+}
+
+// IsResident reports, one bool per page, whether that page is currently
+// resident in memory.
+func (m MMap) IsResident() ([]bool, error) {
+	return m.isResident()
+}
+
+// LockRange pins the pages covering [offset, offset+length) in memory,
+// preventing them from being swapped out.
+func (m MMap) LockRange(offset, length int) error {
+	return m.lockRange(offset, length)
+}
+
+// UnlockRange undoes a prior LockRange.
+func (m MMap) UnlockRange(offset, length int) error {
+	return m.unlockRange(offset, length)
+}
@@ -0,0 +1,44 @@
+package mmap
+
+import "testing"
+
+func TestAdviseAndLockRangeAreNoOpsOnWASM(t *testing.T) {
+	data, err := mmap(16, RDWR, ANON, 0, 0)
+	if err != nil {
+		t.Fatalf("mmap: %v", err)
+	}
+	m := MMap(data)
+	defer m.unmap()
+
+	if err := m.Advise(AdviseSequential); err != nil {
+		t.Fatalf("Advise: %v", err)
+	}
+	if err := m.LockRange(0, 8); err != nil {
+		t.Fatalf("LockRange: %v", err)
+	}
+	if err := m.UnlockRange(0, 8); err != nil {
+		t.Fatalf("UnlockRange: %v", err)
+	}
+}
+
+func TestIsResidentReportsAllPagesResident(t *testing.T) {
+	data, err := mmap(wasmPageSize*2+1, RDWR, ANON, 0, 0)
+	if err != nil {
+		t.Fatalf("mmap: %v", err)
+	}
+	m := MMap(data)
+	defer m.unmap()
+
+	resident, err := m.IsResident()
+	if err != nil {
+		t.Fatalf("IsResident: %v", err)
+	}
+	if len(resident) != 3 {
+		t.Fatalf("len(resident) = %d, want 3", len(resident))
+	}
+	for i, r := range resident {
+		if !r {
+			t.Fatalf("resident[%d] = false, want true", i)
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package mmap
+
+import (
+	"encoding/binary"
+	"errors"
+	"strings"
+)
+
+// This file is named mmap_accessors_wasm.go, not a plain
+// mmap_accessors.go, because the write paths below call this backend's
+// checkWritable and markDirty. Other platforms need their own copy of
+// this file alongside their own checkWritable (which can simply return
+// nil, since a real mmap already enforces write permission at the
+// page-table level) and markDirty.
+
+// ErrIndexOutOfBound is returned by the typed accessors below when the
+// requested offset (plus the size of the value being read or written)
+// falls outside the mapped region.
+var ErrIndexOutOfBound = errors.New("mmap: index out of bound")
+
+// ReadUint64At reads a little-endian uint64 starting at offset.
+func (m MMap) ReadUint64At(offset int64) (uint64, error) {
+	if offset < 0 || offset > int64(len(m))-8 {
+		return 0, ErrIndexOutOfBound
+	}
+	return binary.LittleEndian.Uint64(m[offset : offset+8]), nil
+}
+
+// WriteUint64At writes v as a little-endian uint64 starting at offset.
+func (m MMap) WriteUint64At(v uint64, offset int64) error {
+	if offset < 0 || offset > int64(len(m))-8 {
+		return ErrIndexOutOfBound
+	}
+	if err := checkWritable(m); err != nil {
+		return err
+	}
+	binary.LittleEndian.PutUint64(m[offset:offset+8], v)
+	markDirty(m, int(offset), 8)
+	return nil
+}
+
+// ReadUint32At reads a little-endian uint32 starting at offset.
+func (m MMap) ReadUint32At(offset int64) (uint32, error) {
+	if offset < 0 || offset > int64(len(m))-4 {
+		return 0, ErrIndexOutOfBound
+	}
+	return binary.LittleEndian.Uint32(m[offset : offset+4]), nil
+}
+
+// WriteUint32At writes v as a little-endian uint32 starting at offset.
+func (m MMap) WriteUint32At(v uint32, offset int64) error {
+	if offset < 0 || offset > int64(len(m))-4 {
+		return ErrIndexOutOfBound
+	}
+	if err := checkWritable(m); err != nil {
+		return err
+	}
+	binary.LittleEndian.PutUint32(m[offset:offset+4], v)
+	markDirty(m, int(offset), 4)
+	return nil
+}
+
+// ReadStringAt reads a string previously written with WriteStringAt —
+// an 8-byte little-endian length prefix followed by that many bytes —
+// and appends it to dest. It returns the total number of bytes consumed
+// from the mapping (8 + len(string)).
+//
+// The length prefix is untrusted data read from the mapping itself, so
+// it's checked against len(m) before any arithmetic that could
+// otherwise overflow and bypass the bounds check.
+func (m MMap) ReadStringAt(dest *strings.Builder, offset int64) (int, error) {
+	length, err := m.ReadUint64At(offset)
+	if err != nil {
+		return 0, err
+	}
+	if length > uint64(len(m)) {
+		return 0, ErrIndexOutOfBound
+	}
+
+	start := offset + 8
+	end := start + int64(length)
+	if end > int64(len(m)) {
+		return 0, ErrIndexOutOfBound
+	}
+
+	dest.Write(m[start:end])
+	return 8 + int(length), nil
+}
+
+// WriteStringAt writes src at offset as an 8-byte little-endian length
+// prefix followed by its bytes, so it can later be read back with
+// ReadStringAt. It returns the total number of bytes written to the
+// mapping (8 + len(src)).
+func (m MMap) WriteStringAt(src string, offset int64) (int, error) {
+	if offset < 0 || offset > int64(len(m))-8 {
+		return 0, ErrIndexOutOfBound
+	}
+	remaining := int64(len(m)) - offset - 8
+	if int64(len(src)) > remaining {
+		return 0, ErrIndexOutOfBound
+	}
+
+	if err := m.WriteUint64At(uint64(len(src)), offset); err != nil {
+		return 0, err
+	}
+	end := offset + 8 + int64(len(src))
+	copy(m[offset+8:end], src)
+	markDirty(m, int(offset)+8, len(src))
+	return 8 + len(src), nil
+}
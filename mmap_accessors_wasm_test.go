@@ -0,0 +1,112 @@
+package mmap
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+// newAnonMapping creates a WASM-backed anonymous mapping the same way
+// Map would for an ANON request, so accessor tests exercise the real
+// write-guard/registration path rather than a bare byte slice.
+func newAnonMapping(t *testing.T, length int, prot uintptr) MMap {
+	t.Helper()
+	data, err := mmap(length, prot, ANON, 0, 0)
+	if err != nil {
+		t.Fatalf("mmap: %v", err)
+	}
+	m := MMap(data)
+	t.Cleanup(func() {
+		if err := m.unmap(); err != nil {
+			t.Errorf("unmap: %v", err)
+		}
+	})
+	return m
+}
+
+func TestReadWriteUint64At(t *testing.T) {
+	m := newAnonMapping(t, 16, RDWR)
+	if err := m.WriteUint64At(0x0102030405060708, 4); err != nil {
+		t.Fatalf("WriteUint64At: %v", err)
+	}
+	got, err := m.ReadUint64At(4)
+	if err != nil {
+		t.Fatalf("ReadUint64At: %v", err)
+	}
+	if got != 0x0102030405060708 {
+		t.Fatalf("got %#x, want %#x", got, 0x0102030405060708)
+	}
+}
+
+func TestReadWriteUint32At(t *testing.T) {
+	m := newAnonMapping(t, 8, RDWR)
+	if err := m.WriteUint32At(0x0a0b0c0d, 2); err != nil {
+		t.Fatalf("WriteUint32At: %v", err)
+	}
+	got, err := m.ReadUint32At(2)
+	if err != nil {
+		t.Fatalf("ReadUint32At: %v", err)
+	}
+	if got != 0x0a0b0c0d {
+		t.Fatalf("got %#x, want %#x", got, 0x0a0b0c0d)
+	}
+}
+
+func TestStringAtRoundTrip(t *testing.T) {
+	m := newAnonMapping(t, 64, RDWR)
+	n, err := m.WriteStringAt("hello, mmap", 8)
+	if err != nil {
+		t.Fatalf("WriteStringAt: %v", err)
+	}
+
+	var sb strings.Builder
+	read, err := m.ReadStringAt(&sb, 8)
+	if err != nil {
+		t.Fatalf("ReadStringAt: %v", err)
+	}
+	if read != n {
+		t.Fatalf("ReadStringAt consumed %d bytes, WriteStringAt wrote %d", read, n)
+	}
+	if sb.String() != "hello, mmap" {
+		t.Fatalf("got %q", sb.String())
+	}
+}
+
+// TestBoundsChecksDoNotOverflow exercises offsets/lengths large enough
+// that offset+size or start+length would wrap around int64 if computed
+// with addition before the bounds check, which would let the accessors'
+// slice expressions panic instead of returning ErrIndexOutOfBound.
+func TestBoundsChecksDoNotOverflow(t *testing.T) {
+	m := newAnonMapping(t, 16, RDWR)
+	nearMax := int64(math.MaxInt64) - 3
+
+	if _, err := m.ReadUint64At(nearMax); err != ErrIndexOutOfBound {
+		t.Fatalf("ReadUint64At(nearMax) = %v, want ErrIndexOutOfBound", err)
+	}
+	if err := m.WriteUint64At(1, nearMax); err != ErrIndexOutOfBound {
+		t.Fatalf("WriteUint64At(nearMax) = %v, want ErrIndexOutOfBound", err)
+	}
+	if _, err := m.ReadUint32At(nearMax); err != ErrIndexOutOfBound {
+		t.Fatalf("ReadUint32At(nearMax) = %v, want ErrIndexOutOfBound", err)
+	}
+	if err := m.WriteUint32At(1, nearMax); err != ErrIndexOutOfBound {
+		t.Fatalf("WriteUint32At(nearMax) = %v, want ErrIndexOutOfBound", err)
+	}
+
+	// A corrupted length prefix near math.MaxUint64 must not be able to
+	// make ReadStringAt's internal arithmetic wrap past the check.
+	if err := m.WriteUint64At(math.MaxUint64-3, 0); err != nil {
+		t.Fatalf("WriteUint64At: %v", err)
+	}
+	var sb strings.Builder
+	if _, err := m.ReadStringAt(&sb, 0); err != ErrIndexOutOfBound {
+		t.Fatalf("ReadStringAt(corrupted length) = %v, want ErrIndexOutOfBound", err)
+	}
+}
+
+func TestWriteAtRejectsReadOnlyMapping(t *testing.T) {
+	m := newAnonMapping(t, 16, RDONLY)
+	if err := m.WriteUint64At(1, 0); err == nil {
+		t.Fatal("WriteUint64At on a read-only mapping succeeded, want an error")
+	}
+}
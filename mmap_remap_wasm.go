@@ -0,0 +1,14 @@
+package mmap
+
+// Remap grows or shrinks m to newLength without requiring the caller to
+// Unmap and re-Mmap. On this (WASM) backend that means reallocating the
+// underlying slice and re-keying it in the mappings table; other
+// backends resize in place (mremap(2)) or emulate it via
+// truncate+unmap+remap, preserving prot/flags/offset either way.
+//
+// This file is named mmap_remap_wasm.go, not a plain mmap_remap.go,
+// because it's only valid to call m.remap on the WASM backend; other
+// platforms provide their own Remap alongside their own remap.
+func (m *MMap) Remap(newLength int) error {
+	return m.remap(newLength)
+}
@@ -0,0 +1,73 @@
+package mmap
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestRemapGrowsAndPreservesContent(t *testing.T) {
+	data, err := mmap(8, RDWR, ANON, 0, 0)
+	if err != nil {
+		t.Fatalf("mmap: %v", err)
+	}
+	m := MMap(data)
+	copy(m, "abcdefgh")
+
+	if err := m.remap(16); err != nil {
+		t.Fatalf("remap(16): %v", err)
+	}
+	if len(m) != 16 {
+		t.Fatalf("len(m) = %d, want 16", len(m))
+	}
+	if !bytes.Equal(m[:8], []byte("abcdefgh")) {
+		t.Fatalf("original content not preserved after growing: %q", m[:8])
+	}
+
+	if err := m.unmap(); err != nil {
+		t.Fatalf("unmap: %v", err)
+	}
+}
+
+func TestRemapShrinks(t *testing.T) {
+	data, err := mmap(16, RDWR, ANON, 0, 0)
+	if err != nil {
+		t.Fatalf("mmap: %v", err)
+	}
+	m := MMap(data)
+	copy(m, "0123456789abcdef")
+
+	if err := m.remap(4); err != nil {
+		t.Fatalf("remap(4): %v", err)
+	}
+	if len(m) != 4 {
+		t.Fatalf("len(m) = %d, want 4", len(m))
+	}
+	if !bytes.Equal(m, []byte("0123")) {
+		t.Fatalf("got %q", m)
+	}
+
+	if err := m.unmap(); err != nil {
+		t.Fatalf("unmap: %v", err)
+	}
+}
+
+// TestRemapRejectsReadOnlyMapping checks that remap refuses to resize a
+// mapping that wasn't created writable, consistent with the guard used
+// by the typed accessors.
+func TestRemapRejectsReadOnlyMapping(t *testing.T) {
+	data, err := mmap(8, RDONLY, ANON, 0, 0)
+	if err != nil {
+		t.Fatalf("mmap: %v", err)
+	}
+	m := MMap(data)
+
+	if err := m.remap(16); err == nil {
+		t.Fatal("remap on a read-only mapping succeeded, want an error")
+	}
+
+	// The mapping must still be usable afterwards: remap must not have
+	// removed it from the registry on this rejected attempt.
+	if err := m.unmap(); err != nil {
+		t.Fatalf("unmap after rejected remap: %v", err)
+	}
+}
@@ -2,6 +2,7 @@ package mmap
 
 import (
 	"errors"
+	"fmt"
 	"io"
 	"os"
 	"sync"
@@ -11,19 +12,61 @@ import (
 // WASM doesn't support traditional memory mapping, so we simulate it
 // by reading the entire file into memory.
 
+// ErrFlushCopyOnWrite is returned by Flush for a COPY (MAP_PRIVATE-style)
+// mapping. Real mmap never writes a private mapping's changes back to
+// the file either; unlike the POSIX call, Flush can report that
+// explicitly instead of silently discarding the write.
+var ErrFlushCopyOnWrite = errors.New("mmap: cannot flush a copy-on-write mapping")
+
+// ErrReadOnlyMapping is returned by mmap-go's own write paths when a
+// mapping wasn't opened with PROT_WRITE. WASM has no page-protection
+// mechanism to make a stray write trap at the hardware level the way a
+// real mmap does, so this is only enforced by code that routes through
+// these accessors (WriteUint64At, WriteStringAt, remap, ...) — writing
+// directly into the returned []byte bypasses it, since Go has no way
+// to guard an arbitrary slice write.
+var ErrReadOnlyMapping = errors.New("mmap: mapping is not writable")
+
 type wasmMapping struct {
 	data        []byte
 	file        *os.File
 	writable    bool
 	copyOnWrite bool
+	anon        bool
 	offset      int64
+
+	// original and dirty only apply to copyOnWrite mappings: original is
+	// the file content as of map time (or the last revalidate), and
+	// dirty tracks which bytes the mapping has since diverged on, so a
+	// future revalidate can refresh everything else from the file
+	// without clobbering local writes. Both are maintained on a
+	// best-effort basis: only writes made through this package's own
+	// accessors mark dirty, since Go cannot intercept a direct m[i] = x
+	// write into the mapped slice.
+	original []byte
+	dirty    []bool
 }
 
 var (
 	mappings   = make(map[uintptr]*wasmMapping)
 	mappingsMu sync.Mutex
+
+	fdOpenFlags   = make(map[uintptr]int)
+	fdOpenFlagsMu sync.Mutex
 )
 
+// NoteOpenFlags records the flags a file was opened with (as passed to
+// os.OpenFile) against its descriptor, so the WASM backend can later
+// tell whether the fd given to Map/MapRegion is actually writable. This
+// replaces a test write against the fd, which doesn't reliably fail on
+// a read-only fd on every platform. Call it right after OpenFile, before
+// handing the file to Map/MapRegion.
+func NoteOpenFlags(file *os.File, flags int) {
+	fdOpenFlagsMu.Lock()
+	fdOpenFlags[file.Fd()] = flags
+	fdOpenFlagsMu.Unlock()
+}
+
 func mmap(length int, prot, flags, fd uintptr, offset int64) ([]byte, error) {
 	// WASM doesn't support anonymous mappings in the traditional sense
 	if flags&ANON != 0 {
@@ -33,6 +76,7 @@ func mmap(length int, prot, flags, fd uintptr, offset int64) ([]byte, error) {
 		mappings[uintptr(unsafe.Pointer(&data[0]))] = &wasmMapping{
 			data:     data,
 			writable: prot&RDWR != 0 || prot&COPY != 0,
+			anon:     true,
 		}
 		mappingsMu.Unlock()
 		return data, nil
@@ -44,15 +88,26 @@ func mmap(length int, prot, flags, fd uintptr, offset int64) ([]byte, error) {
 		return nil, errors.New("invalid file descriptor")
 	}
 
-	// Check if file is read-only when RDWR is requested
+	// Check whether the fd actually allows writing when RDWR is
+	// requested. A test write isn't reliable here (an empty write can
+	// succeed against a read-only fd on some platforms), so check the
+	// file's permission bits plus whatever open flags were stashed for
+	// this fd via NoteOpenFlags.
 	if prot&RDWR != 0 {
-		// Try a test write to check permissions
-		testPos, _ := file.Seek(0, io.SeekCurrent)
-		_, err := file.Write([]byte{})
+		stat, err := file.Stat()
 		if err != nil {
 			return nil, err
 		}
-		file.Seek(testPos, io.SeekStart)
+		if stat.Mode().Perm()&0200 == 0 {
+			return nil, errors.New("mmap: file permissions do not allow writing")
+		}
+
+		fdOpenFlagsMu.Lock()
+		openFlags, stashed := fdOpenFlags[fd]
+		fdOpenFlagsMu.Unlock()
+		if stashed && openFlags&(os.O_RDWR|os.O_WRONLY) == 0 {
+			return nil, errors.New("mmap: file was not opened for writing")
+		}
 	}
 
 	// Seek to the offset
@@ -83,15 +138,27 @@ func mmap(length int, prot, flags, fd uintptr, offset int64) ([]byte, error) {
 	// Reset file position to beginning for subsequent reads
 	file.Seek(0, io.SeekStart)
 
-	// Store mapping info
-	mappingsMu.Lock()
-	mappings[uintptr(unsafe.Pointer(&data[0]))] = &wasmMapping{
+	mapping := &wasmMapping{
 		data:        data,
 		file:        file,
 		writable:    prot&RDWR != 0 && prot&COPY == 0,
 		copyOnWrite: prot&COPY != 0,
 		offset:      offset,
 	}
+
+	if mapping.copyOnWrite {
+		// A private mapping is writable in memory regardless of RDWR,
+		// since writes never leave the process; they just never reach
+		// the file. Keep a snapshot of the file content plus a dirty
+		// bitmap so a later revalidate knows which bytes it can safely
+		// refresh from the file.
+		mapping.writable = true
+		mapping.original = append([]byte(nil), data...)
+		mapping.dirty = make([]bool, len(data))
+	}
+
+	mappingsMu.Lock()
+	mappings[uintptr(unsafe.Pointer(&data[0]))] = mapping
 	mappingsMu.Unlock()
 
 	return data, nil
@@ -110,8 +177,13 @@ func (m MMap) flush() error {
 		return errors.New("mapping not found")
 	}
 
-	// Only flush if writable and backed by a file and not copy-on-write
-	if mapping.writable && mapping.file != nil && !mapping.copyOnWrite {
+	// Real mmap never writes a MAP_PRIVATE mapping's changes back to the
+	// file; say so explicitly rather than quietly reporting success.
+	if mapping.copyOnWrite {
+		return ErrFlushCopyOnWrite
+	}
+
+	if mapping.writable && mapping.file != nil {
 		// Save current position
 		savedPos, _ := mapping.file.Seek(0, io.SeekCurrent)
 
@@ -143,13 +215,213 @@ func (m MMap) unlock() error {
 	return nil
 }
 
+// wasmPageSize is used only to size the all-resident report below; WASM
+// has no real notion of a hardware page for a mapping that already
+// lives entirely in the Go heap.
+const wasmPageSize = 4096
+
+// advise is a no-op on WASM: the mapping is plain heap memory, not
+// something the runtime's page cache can be hinted about.
+func (m MMap) advise(advice int) error {
+	return nil
+}
+
+// isResident reports every page as resident, since a WASM mapping is
+// fully materialized in memory from the moment it's created.
+func (m MMap) isResident() ([]bool, error) {
+	n := (len(m) + wasmPageSize - 1) / wasmPageSize
+	resident := make([]bool, n)
+	for i := range resident {
+		resident[i] = true
+	}
+	return resident, nil
+}
+
+// lockRange and unlockRange are no-ops on WASM: there's no virtual
+// memory subsystem to pin pages against.
+func (m MMap) lockRange(offset, length int) error {
+	return nil
+}
+
+func (m MMap) unlockRange(offset, length int) error {
+	return nil
+}
+
+// lookupMapping finds the wasmMapping backing m and, when
+// requireWritable is set, requires it to be writable. Used by write
+// paths that want ErrReadOnlyMapping instead of silently succeeding or
+// relying on Go to catch the mistake for them.
+func lookupMapping(m MMap, requireWritable bool) (*wasmMapping, error) {
+	if len(m) == 0 {
+		return nil, errors.New("mapping not found")
+	}
+
+	mappingsMu.Lock()
+	mapping, ok := mappings[uintptr(unsafe.Pointer(&m[0]))]
+	mappingsMu.Unlock()
+
+	if !ok {
+		return nil, errors.New("mapping not found")
+	}
+	if requireWritable && !mapping.writable {
+		if mapping.anon {
+			return nil, fmt.Errorf("mmap: anonymous mapping is not writable: %w", ErrReadOnlyMapping)
+		}
+		return nil, ErrReadOnlyMapping
+	}
+	return mapping, nil
+}
+
+// checkWritable reports whether m was mapped writable, returning
+// ErrReadOnlyMapping if not. WASM has no page-protection mechanism to
+// make a write to a read-only mapping trap at the hardware level the
+// way a real mmap does, so this is the guard mmap-go's own write paths
+// (WriteUint64At, WriteStringAt, ...) call before mutating m; it
+// honors RDWR vs read-only prot for anonymous mappings as well as
+// file-backed ones, since both are tracked the same way in
+// wasmMapping.writable. Writing directly into the returned []byte
+// still bypasses it — Go has no way to guard an arbitrary slice write.
+func checkWritable(m MMap) error {
+	_, err := lookupMapping(m, true)
+	return err
+}
+
+// markDirty records that m has been written to, through one of this
+// package's own accessors, over [offset, offset+length). Only
+// copy-on-write mappings track this; it's a no-op otherwise. Accessor
+// write paths (WriteUint64At, WriteUint32At, WriteStringAt) must call
+// this after every successful write, or a later Revalidate would
+// silently clobber the write with stale file bytes.
+func markDirty(m MMap, offset, length int) {
+	mappingsMu.Lock()
+	mapping, ok := mappings[uintptr(unsafe.Pointer(&m[0]))]
+	mappingsMu.Unlock()
+
+	if !ok || mapping.dirty == nil {
+		return
+	}
+	end := offset + length
+	if end > len(mapping.dirty) {
+		end = len(mapping.dirty)
+	}
+	for i := offset; i < end; i++ {
+		mapping.dirty[i] = true
+	}
+}
+
+// revalidate refreshes the bytes of a copy-on-write mapping that the
+// mapping hasn't been written to (per its dirty bitmap) from the
+// current contents of the backing file, leaving dirty bytes untouched —
+// matching MAP_PRIVATE semantics where local writes always win.
+func (m MMap) revalidate() error {
+	mapping, err := lookupMapping(m, false)
+	if err != nil {
+		return err
+	}
+	if !mapping.copyOnWrite || mapping.file == nil {
+		return nil
+	}
+
+	fresh := make([]byte, len(mapping.data))
+	_, err = mapping.file.ReadAt(fresh, mapping.offset)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return err
+	}
+
+	for i := range mapping.data {
+		if i < len(fresh) && !mapping.dirty[i] {
+			mapping.data[i] = fresh[i]
+			mapping.original[i] = fresh[i]
+		}
+	}
+	return nil
+}
+
+// Revalidate refreshes the bytes of a copy-on-write mapping that
+// haven't been written to since it was mapped (or last revalidated)
+// with the backing file's current contents, leaving any bytes written
+// through this package's own accessors untouched. It has no effect on
+// a mapping that isn't copy-on-write.
+func (m MMap) Revalidate() error {
+	return m.revalidate()
+}
+
+// remap grows or shrinks an existing WASM-backed mapping in place. Since
+// resizing the underlying slice can reallocate it, the mapping must be
+// re-keyed in mappings under mappingsMu so later flush/unmap/lock calls
+// still find it by the (possibly new) backing pointer.
+func (m *MMap) remap(newLength int) error {
+	if newLength <= 0 {
+		return errors.New("invalid length")
+	}
+
+	mapping, err := lookupMapping(*m, true)
+	if err != nil {
+		return err
+	}
+
+	mappingsMu.Lock()
+	delete(mappings, uintptr(unsafe.Pointer(&mapping.data[0])))
+	mappingsMu.Unlock()
+
+	switch {
+	case newLength == len(mapping.data):
+		// Nothing to do beyond putting the entry back under its
+		// existing key.
+	case newLength < len(mapping.data):
+		mapping.data = mapping.data[:newLength]
+		if mapping.dirty != nil {
+			mapping.dirty = mapping.dirty[:newLength]
+		}
+	default:
+		grown := make([]byte, newLength)
+		copy(grown, mapping.data)
+		if mapping.file != nil {
+			fillFrom := mapping.offset + int64(len(mapping.data))
+			_, err := mapping.file.ReadAt(grown[len(mapping.data):], fillFrom)
+			if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+				// Put the mapping back under its original key before
+				// bailing out: m still points at mapping.data (the old,
+				// unresized slice), and it must stay findable by later
+				// Flush/Unmap/Lock calls.
+				mappingsMu.Lock()
+				mappings[uintptr(unsafe.Pointer(&mapping.data[0]))] = mapping
+				mappingsMu.Unlock()
+				return err
+			}
+		}
+		mapping.data = grown
+
+		if mapping.dirty != nil {
+			oldLen := len(mapping.dirty)
+			dirty := make([]bool, newLength)
+			copy(dirty, mapping.dirty)
+			mapping.dirty = dirty
+
+			original := make([]byte, newLength)
+			copy(original, mapping.original)
+			copy(original[oldLen:], grown[oldLen:])
+			mapping.original = original
+		}
+	}
+
+	mappingsMu.Lock()
+	mappings[uintptr(unsafe.Pointer(&mapping.data[0]))] = mapping
+	mappingsMu.Unlock()
+
+	*m = mapping.data
+	return nil
+}
+
 func (m MMap) unmap() error {
 	if len(m) == 0 {
 		return nil
 	}
 
-	// Flush changes first
-	if err := m.flush(); err != nil {
+	// Flush changes first, ignoring the copy-on-write mapping's explicit
+	// "can't flush" error: unmapping a private mapping is still supposed
+	// to succeed, it just never had anything to write back.
+	if err := m.flush(); err != nil && err != ErrFlushCopyOnWrite {
 		return err
 	}
 
@@ -164,9 +436,17 @@ func (m MMap) unmap() error {
 
 	// Close the file if it exists
 	if mapping.file != nil {
+		// Fd is only valid until Close returns, so grab it first; clear
+		// the stashed open flags for it too, otherwise they leak for
+		// the life of the process and, worse, get handed to whatever
+		// unrelated file the OS later opens on the same fd number.
+		fd := mapping.file.Fd()
 		if err := mapping.file.Close(); err != nil {
 			return err
 		}
+		fdOpenFlagsMu.Lock()
+		delete(fdOpenFlags, fd)
+		fdOpenFlagsMu.Unlock()
 	}
 
 	// Remove from mappings
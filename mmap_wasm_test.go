@@ -0,0 +1,105 @@
+package mmap
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFlushOnCopyOnWriteMappingReturnsExplicitError(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "data")
+	if err := os.WriteFile(path, []byte("0123456789"), 0o644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	f, err := os.OpenFile(path, os.O_RDWR, 0)
+	if err != nil {
+		t.Fatalf("OpenFile: %v", err)
+	}
+	NoteOpenFlags(f, os.O_RDWR)
+
+	data, err := mmap(-1, RDWR|COPY, 0, f.Fd(), 0)
+	if err != nil {
+		t.Fatalf("mmap: %v", err)
+	}
+	m := MMap(data)
+	copy(m, "abcde")
+
+	if err := m.flush(); err != ErrFlushCopyOnWrite {
+		t.Fatalf("flush() = %v, want ErrFlushCopyOnWrite", err)
+	}
+
+	// unmap must still succeed: it has nothing to flush for a private
+	// mapping, not an error to report.
+	if err := m.unmap(); err != nil {
+		t.Fatalf("unmap: %v", err)
+	}
+
+	// The file on disk must be untouched by the private mapping's write.
+	onDisk, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	if string(onDisk) != "0123456789" {
+		t.Fatalf("file content changed by copy-on-write mapping: %q", onDisk)
+	}
+}
+
+func TestRevalidatePreservesDirtyBytesAndRefreshesTheRest(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "data")
+	if err := os.WriteFile(path, []byte("0123456789"), 0o644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	f, err := os.OpenFile(path, os.O_RDWR, 0)
+	if err != nil {
+		t.Fatalf("OpenFile: %v", err)
+	}
+	NoteOpenFlags(f, os.O_RDWR)
+
+	data, err := mmap(-1, RDWR|COPY, 0, f.Fd(), 0)
+	if err != nil {
+		t.Fatalf("mmap: %v", err)
+	}
+	m := MMap(data)
+	defer m.unmap()
+
+	// Write through the tracked accessor path at offset 0 (marks [0,8)
+	// dirty) and leave bytes [8,10) untouched, so they stay clean.
+	if err := m.WriteUint64At(0, 0); err != nil {
+		t.Fatalf("WriteUint64At: %v", err)
+	}
+
+	// Someone else changes the file underneath the mapping.
+	if err := os.WriteFile(path, []byte("ZZZZZZZZZZ"), 0o644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	if err := m.Revalidate(); err != nil {
+		t.Fatalf("Revalidate: %v", err)
+	}
+
+	// The dirty prefix must survive Revalidate...
+	for i := 0; i < 8; i++ {
+		if m[i] != 0 {
+			t.Fatalf("m[%d] = %d, want 0 (dirty byte clobbered by Revalidate)", i, m[i])
+		}
+	}
+	// ...while the untouched suffix picks up the new file contents.
+	for i := 8; i < 10; i++ {
+		if m[i] != 'Z' {
+			t.Fatalf("m[%d] = %q, want 'Z' (clean byte not refreshed by Revalidate)", i, m[i])
+		}
+	}
+}
+
+func TestWriteGuardRejectsReadOnlyAnonymousMapping(t *testing.T) {
+	data, err := mmap(8, RDONLY, ANON, 0, 0)
+	if err != nil {
+		t.Fatalf("mmap: %v", err)
+	}
+	m := MMap(data)
+	defer m.unmap()
+
+	if err := checkWritable(m); err == nil {
+		t.Fatal("checkWritable on a read-only anonymous mapping returned nil, want an error")
+	}
+}
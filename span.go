@@ -0,0 +1,131 @@
+package mmap
+
+import (
+	"errors"
+	"io"
+	"sort"
+)
+
+var errOffset = errors.New("mmap: negative offset")
+
+// Span presents N independently-mapped MMap regions as a single flat
+// address range, the way torrent-style storage maps each file in a
+// torrent separately but wants one contiguous piece space. Reads and
+// writes that straddle a boundary between two underlying mappings are
+// split and dispatched to each one in turn.
+type Span struct {
+	maps    []MMap
+	offsets []int64 // start offset of maps[i] within the span
+	size    int64
+}
+
+// NewSpan concatenates maps, in order, into a single logical MMap region.
+func NewSpan(maps ...MMap) *Span {
+	offsets := make([]int64, len(maps))
+	var size int64
+	for i, m := range maps {
+		offsets[i] = size
+		size += int64(len(m))
+	}
+	return &Span{maps: maps, offsets: offsets, size: size}
+}
+
+// Size returns the combined length of all underlying mappings.
+func (s *Span) Size() int64 {
+	return s.size
+}
+
+// segmentFor returns the index of the mapping containing span offset off.
+func (s *Span) segmentFor(off int64) int {
+	return sort.Search(len(s.offsets), func(i int) bool {
+		end := s.size
+		if i+1 < len(s.offsets) {
+			end = s.offsets[i+1]
+		}
+		return off < end
+	})
+}
+
+// ReadAt implements io.ReaderAt over the concatenated mappings.
+func (s *Span) ReadAt(p []byte, off int64) (int, error) {
+	if off < 0 {
+		return 0, errOffset
+	}
+	if off >= s.size {
+		if off == s.size && len(p) == 0 {
+			return 0, nil
+		}
+		return 0, io.EOF
+	}
+
+	n := 0
+	idx := s.segmentFor(off)
+	for n < len(p) && idx < len(s.maps) {
+		seg := s.maps[idx]
+		segOff := off + int64(n) - s.offsets[idx]
+
+		avail := len(seg) - int(segOff)
+		want := len(p) - n
+		if want > avail {
+			want = avail
+		}
+
+		copy(p[n:n+want], seg[segOff:segOff+int64(want)])
+		n += want
+		idx++
+	}
+
+	if n < len(p) {
+		return n, io.EOF
+	}
+	return n, nil
+}
+
+// WriteAt implements io.WriterAt over the concatenated mappings.
+func (s *Span) WriteAt(p []byte, off int64) (int, error) {
+	if off < 0 {
+		return 0, errOffset
+	}
+	if off+int64(len(p)) > s.size {
+		return 0, io.ErrShortWrite
+	}
+
+	n := 0
+	idx := s.segmentFor(off)
+	for n < len(p) {
+		seg := s.maps[idx]
+		segOff := off + int64(n) - s.offsets[idx]
+
+		avail := len(seg) - int(segOff)
+		want := len(p) - n
+		if want > avail {
+			want = avail
+		}
+
+		copy(seg[segOff:segOff+int64(want)], p[n:n+want])
+		n += want
+		idx++
+	}
+
+	return n, nil
+}
+
+// Flush flushes every underlying mapping to its backing file.
+func (s *Span) Flush() error {
+	for _, m := range s.maps {
+		if err := m.Flush(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Close unmaps every underlying mapping.
+func (s *Span) Close() error {
+	for _, m := range s.maps {
+		if err := m.Unmap(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+package mmap
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestSpanReadAtStraddlesMappings(t *testing.T) {
+	a := MMap([]byte("0123"))
+	b := MMap([]byte("4567"))
+	c := MMap([]byte("89"))
+	s := NewSpan(a, b, c)
+
+	if got, want := s.Size(), int64(10); got != want {
+		t.Fatalf("Size() = %d, want %d", got, want)
+	}
+
+	buf := make([]byte, 6)
+	n, err := s.ReadAt(buf, 2)
+	if err != nil {
+		t.Fatalf("ReadAt: %v", err)
+	}
+	if n != 6 {
+		t.Fatalf("n = %d, want 6", n)
+	}
+	if !bytes.Equal(buf, []byte("234567")) {
+		t.Fatalf("got %q, want %q", buf, "234567")
+	}
+}
+
+func TestSpanWriteAtStraddlesMappings(t *testing.T) {
+	a := MMap(make([]byte, 4))
+	b := MMap(make([]byte, 4))
+	c := MMap(make([]byte, 2))
+	s := NewSpan(a, b, c)
+
+	n, err := s.WriteAt([]byte("abcdefgh"), 1)
+	if err != nil {
+		t.Fatalf("WriteAt: %v", err)
+	}
+	if n != 8 {
+		t.Fatalf("n = %d, want 8", n)
+	}
+
+	if !bytes.Equal(a, []byte{0, 'a', 'b', 'c'}) {
+		t.Fatalf("segment a = %q", a)
+	}
+	if !bytes.Equal(b, []byte("defg")) {
+		t.Fatalf("segment b = %q", b)
+	}
+	if !bytes.Equal(c, []byte{'h', 0}) {
+		t.Fatalf("segment c = %q", c)
+	}
+}
+
+func TestSpanWriteAtPastEndFails(t *testing.T) {
+	s := NewSpan(MMap(make([]byte, 4)))
+	if _, err := s.WriteAt([]byte("too long"), 0); err == nil {
+		t.Fatal("WriteAt past the end of the span succeeded, want an error")
+	}
+}